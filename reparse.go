@@ -8,16 +8,26 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"unicode/utf16"
 	"unsafe"
+
+	"golang.org/x/sys/windows"
 )
 
 const (
 	reparseTagMountPoint = 0xA0000003
 	reparseTagSymlink    = 0xA000000C
 	reparseTagLxSymlink  = 0xA000001D // WSL/MSYS2 native symlinks
+	reparseTagAFUnix     = 0x80000023 // AF_UNIX socket file, created by bind()
 
 	lxSymlinkVersion = 2 // LX symlink format version
+
+	// reparseTagNameSurrogateBit is set in a reparse tag when the reparse
+	// point is a name surrogate for another named entity (a mount point,
+	// an NT symlink, or a third-party reparse point such as a cloud-files
+	// placeholder, a WCI link, or a dedup volume).
+	reparseTagNameSurrogateBit = 0x20000000
 )
 
 type reparseDataBuffer struct {
@@ -32,13 +42,75 @@ type reparseDataBuffer struct {
 
 // ReparsePoint describes a Win32 symlink or mount point.
 type ReparsePoint struct {
-	Target       string
-	IsMountPoint bool
-	IsLxSymlink  bool // True if this is an LX symlink (WSL/MSYS2 native)
+	Target         string
+	IsMountPoint   bool
+	IsLxSymlink    bool // True if this is an LX symlink (WSL/MSYS2 native)
+	IsAFUnixSocket bool // True if this is an AF_UNIX socket file
+
+	// Relative forces an NT symlink to be encoded as relative to its own
+	// location rather than as an absolute NT path. If false, EncodeReparsePoint
+	// falls back to inferring this from Target's shape (a drive letter or
+	// \\ prefix means absolute).
+	Relative bool
+
+	// Generic holds the raw tag and payload of a reparse point whose format
+	// this package does not otherwise understand and for which no decoder
+	// has been registered via RegisterReparseDecoder. It is nil for any
+	// reparse point decoded into one of the fields above.
+	Generic *GenericReparsePoint
+}
+
+// GenericReparsePoint holds the raw tag and payload of a reparse point in a
+// format this package does not parse. It lets callers that merely need to
+// identify or pass through unknown reparse points (for example during a
+// Stat-like directory traversal) do so without failing outright.
+type GenericReparsePoint struct {
+	Tag  uint32
+	Data []byte
+}
+
+// ReparseTag returns the reparse tag of the generic reparse point.
+func (g *GenericReparsePoint) ReparseTag() uint32 {
+	return g.Tag
+}
+
+// IsNameSurrogate reports whether tag identifies a reparse point that is a
+// name surrogate for another named entity, such as a mount point, an NT
+// symlink, or a third-party reparse point like IO_REPARSE_TAG_WCI_LINK,
+// IO_REPARSE_TAG_IIS_CACHE, a dedup volume, or an appx package. Callers
+// walking a filesystem can use this to decide whether to follow a reparse
+// point they don't otherwise understand.
+func IsNameSurrogate(tag uint32) bool {
+	return tag&reparseTagNameSurrogateBit != 0
+}
+
+// ReparseDecoderFunc decodes the payload of a reparse point with a specific
+// tag into a ReparsePoint, for use with RegisterReparseDecoder.
+type ReparseDecoderFunc func([]byte) (*ReparsePoint, error)
+
+var (
+	reparseDecodersMu sync.RWMutex
+	reparseDecoders   = map[uint32]ReparseDecoderFunc{}
+)
+
+// RegisterReparseDecoder registers fn to decode reparse points carrying tag.
+// This lets callers teach DecodeReparsePointData about formats this package
+// does not otherwise understand, such as cloud-files/OneDrive placeholders,
+// WCI, or dedup reparse points, without modifying this module. It has no
+// effect on tags this package already special-cases (mount points, NT
+// symlinks, LX symlinks, and AF_UNIX sockets); those are never looked up in
+// the registry.
+func RegisterReparseDecoder(tag uint32, fn ReparseDecoderFunc) {
+	reparseDecodersMu.Lock()
+	defer reparseDecodersMu.Unlock()
+	reparseDecoders[tag] = fn
 }
 
 // UnsupportedReparsePointError is returned when trying to decode a non-symlink or
 // mount point reparse point.
+//
+// Deprecated: DecodeReparsePointData no longer returns this error for an
+// unrecognized tag; it decodes to a ReparsePoint with Generic set instead.
 type UnsupportedReparsePointError struct {
 	Tag uint32
 }
@@ -47,11 +119,131 @@ func (e *UnsupportedReparsePointError) Error() string {
 	return fmt.Sprintf("unsupported reparse point %x", e.Tag)
 }
 
+// Errors returned while decoding a malformed or hostile reparse point buffer,
+// such as one truncated or crafted by another process.
+var (
+	// ErrTruncatedReparseBuffer is returned when a reparse point buffer is
+	// shorter than its fixed-size header requires.
+	ErrTruncatedReparseBuffer = errors.New("reparse point buffer is truncated")
+	// ErrReparseBufferTooLarge is returned when a reparse point buffer
+	// exceeds MAXIMUM_REPARSE_DATA_BUFFER_SIZE.
+	ErrReparseBufferTooLarge = errors.New("reparse point buffer exceeds the maximum reparse data buffer size")
+	// ErrInvalidReparseDataLength is returned when a reparse point's
+	// ReparseDataLength field does not match the size of the payload
+	// that follows its header.
+	ErrInvalidReparseDataLength = errors.New("reparse point ReparseDataLength does not match its payload size")
+	// ErrInvalidNameOffset is returned when a substitute or print name
+	// offset and length would read outside the reparse point buffer.
+	ErrInvalidNameOffset = errors.New("reparse point name offset is out of range")
+	// ErrOddNameLength is returned when a UTF-16 name field's length is
+	// not a multiple of 2.
+	ErrOddNameLength = errors.New("reparse point name length is not a multiple of 2")
+)
+
+// DecodeReparsePointOptions controls how DecodeReparsePointWithOptions
+// interprets a decoded reparse point.
+type DecodeReparsePointOptions struct {
+	// ResolveVolumeGUID causes a mount point target of the form
+	// \??\Volume{GUID}\... to be resolved to a drive-letter path via
+	// NormalizeNTPath.
+	ResolveVolumeGUID bool
+}
+
 // DecodeReparsePoint decodes a Win32 REPARSE_DATA_BUFFER structure containing either a symlink
 // or a mount point.
 func DecodeReparsePoint(b []byte) (*ReparsePoint, error) {
+	return DecodeReparsePointWithOptions(b, DecodeReparsePointOptions{})
+}
+
+// DecodeReparsePointWithOptions is like DecodeReparsePoint but additionally
+// normalizes mount point targets according to opts.
+func DecodeReparsePointWithOptions(b []byte, opts DecodeReparsePointOptions) (*ReparsePoint, error) {
+	if len(b) > maximumReparseDataBufferSize {
+		return nil, ErrReparseBufferTooLarge
+	}
+	if len(b) < 8 {
+		return nil, ErrTruncatedReparseBuffer
+	}
 	tag := binary.LittleEndian.Uint32(b[0:4])
-	return DecodeReparsePointData(tag, b[8:])
+	reparseDataLength := binary.LittleEndian.Uint16(b[4:6])
+	if int(reparseDataLength) != len(b)-8 {
+		return nil, ErrInvalidReparseDataLength
+	}
+	rp, err := DecodeReparsePointData(tag, b[8:])
+	if err != nil {
+		return nil, err
+	}
+	if opts.ResolveVolumeGUID && rp != nil && rp.IsMountPoint {
+		target, err := NormalizeNTPath(rp.Target)
+		if err != nil {
+			return nil, err
+		}
+		rp.Target = target
+	}
+	return rp, nil
+}
+
+// NormalizeNTPath converts an NT-namespace path (as found in the substitute
+// name of a mount point reparse buffer) back to a usable DOS path. It strips
+// the \??\ prefix, converts \??\UNC\server\share to \\server\share, and for
+// \??\Volume{GUID}\... targets opens the volume and resolves it to a
+// drive-letter path via GetFinalPathNameByHandle.
+func NormalizeNTPath(nt string) (string, error) {
+	const ntPrefix = `\??\`
+	if !strings.HasPrefix(nt, ntPrefix) {
+		return nt, nil
+	}
+	rest := nt[len(ntPrefix):]
+	if strings.HasPrefix(rest, `UNC\`) {
+		return `\\` + rest[len(`UNC\`):], nil
+	}
+	if strings.HasPrefix(rest, `Volume{`) {
+		return resolveVolumeGUIDPath(rest)
+	}
+	return rest, nil
+}
+
+// resolveVolumeGUIDPath resolves a \??\Volume{GUID}\... substitute name
+// (with the \??\ prefix already stripped) to a drive-letter path.
+func resolveVolumeGUIDPath(rest string) (string, error) {
+	win32Path, err := windows.UTF16PtrFromString(`\\?\` + rest)
+	if err != nil {
+		return "", err
+	}
+	h, err := windows.CreateFile(
+		win32Path,
+		0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS|windows.FILE_FLAG_OPEN_REPARSE_POINT,
+		0,
+	)
+	if err != nil {
+		return "", err
+	}
+	defer windows.CloseHandle(h)
+
+	// VOLUME_NAME_DOS, the default GetFinalPathNameByHandle format, resolves
+	// the handle to a drive-letter path such as C:\Users\foo.
+	const volumeNameDOS = 0x0
+
+	buf := make([]uint16, 260)
+	for {
+		n, err := windows.GetFinalPathNameByHandle(h, &buf[0], uint32(len(buf)), volumeNameDOS)
+		if err != nil {
+			return "", err
+		}
+		if int(n) < len(buf) {
+			// The result carries a \\?\ (or \\?\UNC\) prefix; drop it.
+			p := windows.UTF16ToString(buf[:n])
+			if strings.HasPrefix(p, `\\?\UNC\`) {
+				return `\\` + p[len(`\\?\UNC\`):], nil
+			}
+			return strings.TrimPrefix(p, `\\?\`), nil
+		}
+		buf = make([]uint16, n)
+	}
 }
 
 func DecodeReparsePointData(tag uint32, b []byte) (*ReparsePoint, error) {
@@ -62,19 +254,38 @@ func DecodeReparsePointData(tag uint32, b []byte) (*ReparsePoint, error) {
 		return decodeWindowsReparsePointData(b, false)
 	case reparseTagLxSymlink:
 		return decodeLxReparsePointData(b)
+	case reparseTagAFUnix:
+		return &ReparsePoint{IsAFUnixSocket: true}, nil
 	default:
-		return nil, &UnsupportedReparsePointError{tag}
+		reparseDecodersMu.RLock()
+		fn, ok := reparseDecoders[tag]
+		reparseDecodersMu.RUnlock()
+		if ok {
+			return fn(b)
+		}
+		data := make([]byte, len(b))
+		copy(data, b)
+		return &ReparsePoint{Generic: &GenericReparsePoint{Tag: tag, Data: data}}, nil
 	}
 }
 
 func decodeWindowsReparsePointData(b []byte, isMountPoint bool) (*ReparsePoint, error) {
-	nameOffset := 8 + binary.LittleEndian.Uint16(b[4:6])
+	if len(b) < 8 {
+		return nil, ErrTruncatedReparseBuffer
+	}
+	nameOffset := uint32(8) + uint32(binary.LittleEndian.Uint16(b[4:6]))
 	if !isMountPoint {
 		nameOffset += 4
 	}
 	nameLength := binary.LittleEndian.Uint16(b[6:8])
+	if nameLength%2 != 0 {
+		return nil, ErrOddNameLength
+	}
+	if nameOffset+uint32(nameLength) > uint32(len(b)) {
+		return nil, ErrInvalidNameOffset
+	}
 	name := make([]uint16, nameLength/2)
-	err := binary.Read(bytes.NewReader(b[nameOffset:nameOffset+nameLength]), binary.LittleEndian, &name)
+	err := binary.Read(bytes.NewReader(b[nameOffset:nameOffset+uint32(nameLength)]), binary.LittleEndian, &name)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +295,7 @@ func decodeWindowsReparsePointData(b []byte, isMountPoint bool) (*ReparsePoint,
 func decodeLxReparsePointData(b []byte) (*ReparsePoint, error) {
 	// LX symlinks store the target as UTF-8 after a 4-byte version field
 	if len(b) < 4 {
-		return nil, errors.New("LX symlink buffer too short")
+		return nil, ErrTruncatedReparseBuffer
 	}
 	targetBytes := b[4:]
 	for i, c := range targetBytes {
@@ -107,10 +318,23 @@ func EncodeReparsePoint(rp *ReparsePoint) []byte {
 	if rp == nil {
 		return nil
 	}
-	if rp.IsLxSymlink {
+	switch {
+	case rp.IsLxSymlink:
 		return encodeLxReparsePoint(rp)
+	case rp.IsAFUnixSocket:
+		return encodeAFUnixReparsePoint()
+	default:
+		return encodeWindowsReparsePoint(rp)
 	}
-	return encodeWindowsReparsePoint(rp)
+}
+
+func encodeAFUnixReparsePoint() []byte {
+	// The AF_UNIX reparse point carries no payload beyond its header.
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, uint32(reparseTagAFUnix))
+	_ = binary.Write(&b, binary.LittleEndian, uint16(0)) // ReparseDataLength
+	_ = binary.Write(&b, binary.LittleEndian, uint16(0)) // Reserved
+	return b.Bytes()
 }
 
 func encodeLxReparsePoint(rp *ReparsePoint) []byte {
@@ -128,16 +352,21 @@ func encodeLxReparsePoint(rp *ReparsePoint) []byte {
 }
 
 func encodeWindowsReparsePoint(rp *ReparsePoint) []byte {
-	// Generate an NT path and determine if this is a relative path.
+	// Generate an NT path and determine if this is a relative path. An
+	// explicit rp.Relative skips the guesswork; otherwise infer it from
+	// Target's shape.
 	var ntTarget string
-	relative := false
-	if strings.HasPrefix(rp.Target, `\\?\`) {
+	relative := rp.Relative
+	switch {
+	case rp.Relative:
+		ntTarget = rp.Target
+	case strings.HasPrefix(rp.Target, `\\?\`):
 		ntTarget = `\??\` + rp.Target[4:]
-	} else if strings.HasPrefix(rp.Target, `\\`) {
+	case strings.HasPrefix(rp.Target, `\\`):
 		ntTarget = `\??\UNC\` + rp.Target[2:]
-	} else if len(rp.Target) >= 2 && isDriveLetter(rp.Target[0]) && rp.Target[1] == ':' {
+	case len(rp.Target) >= 2 && isDriveLetter(rp.Target[0]) && rp.Target[1] == ':':
 		ntTarget = `\??\` + rp.Target
-	} else {
+	default:
 		ntTarget = rp.Target
 		relative = true
 	}
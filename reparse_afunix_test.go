@@ -0,0 +1,35 @@
+//go:build windows
+
+package winio
+
+import "testing"
+
+func TestAFUnixReparsePointRoundTrip(t *testing.T) {
+	original := &ReparsePoint{IsAFUnixSocket: true}
+
+	encoded := EncodeReparsePoint(original)
+	if len(encoded) != 8 {
+		t.Fatalf("expected an 8-byte header-only buffer, got %d bytes", len(encoded))
+	}
+
+	decoded, err := DecodeReparsePoint(encoded)
+	if err != nil {
+		t.Fatalf("DecodeReparsePoint: %v", err)
+	}
+	if !decoded.IsAFUnixSocket {
+		t.Errorf("expected IsAFUnixSocket, got %+v", decoded)
+	}
+	if decoded.Target != "" {
+		t.Errorf("expected empty Target, got %q", decoded.Target)
+	}
+}
+
+func TestDecodeReparsePointDataAFUnixTag(t *testing.T) {
+	rp, err := DecodeReparsePointData(reparseTagAFUnix, nil)
+	if err != nil {
+		t.Fatalf("DecodeReparsePointData: %v", err)
+	}
+	if !rp.IsAFUnixSocket {
+		t.Errorf("expected IsAFUnixSocket, got %+v", rp)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package winio
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeReparsePointTruncatedHeader(t *testing.T) {
+	_, err := DecodeReparsePoint([]byte{1, 2, 3})
+	if !errors.Is(err, ErrTruncatedReparseBuffer) {
+		t.Fatalf("got %v, want ErrTruncatedReparseBuffer", err)
+	}
+}
+
+func TestDecodeReparsePointTooLarge(t *testing.T) {
+	_, err := DecodeReparsePoint(make([]byte, maximumReparseDataBufferSize+1))
+	if !errors.Is(err, ErrReparseBufferTooLarge) {
+		t.Fatalf("got %v, want ErrReparseBufferTooLarge", err)
+	}
+}
+
+func TestDecodeReparsePointInvalidDataLength(t *testing.T) {
+	encoded := EncodeReparsePoint(&ReparsePoint{Target: `C:\foo`, IsMountPoint: true})
+	// Truncate the payload without updating ReparseDataLength in the header.
+	_, err := DecodeReparsePoint(encoded[:len(encoded)-4])
+	if !errors.Is(err, ErrInvalidReparseDataLength) {
+		t.Fatalf("got %v, want ErrInvalidReparseDataLength", err)
+	}
+}
+
+func TestDecodeWindowsReparsePointDataInvalidNameOffset(t *testing.T) {
+	// PrintNameOffset=0, PrintNameLength=100: the name would read far past
+	// the end of the 8-byte payload, which holds no name data.
+	payload := make([]byte, 8)
+	payload[6] = 100 // PrintNameLength low byte
+	_, err := decodeWindowsReparsePointData(payload, true)
+	if !errors.Is(err, ErrInvalidNameOffset) {
+		t.Fatalf("got %v, want ErrInvalidNameOffset", err)
+	}
+}
+
+func TestDecodeWindowsReparsePointDataOddNameLength(t *testing.T) {
+	payload := make([]byte, 9)
+	payload[6] = 1 // PrintNameLength = 1, not a multiple of 2
+	_, err := decodeWindowsReparsePointData(payload, true)
+	if !errors.Is(err, ErrOddNameLength) {
+		t.Fatalf("got %v, want ErrOddNameLength", err)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build windows
+
+package winio
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// SymlinkFlags controls how CreateSymlink builds a reparse point.
+type SymlinkFlags uint32
+
+const (
+	// SymlinkFlagRelative indicates that target is a path relative to the
+	// symlink's own location rather than an absolute path.
+	SymlinkFlagRelative SymlinkFlags = 1 << iota
+	// SymlinkFlagDirectory indicates that the symlink refers to a directory
+	// rather than a file.
+	SymlinkFlagDirectory
+)
+
+const (
+	fsctlGetReparsePoint = 0x000900A8
+	fsctlSetReparsePoint = 0x000900A4
+
+	// maximumReparseDataBufferSize is MAXIMUM_REPARSE_DATA_BUFFER_SIZE from
+	// the Windows SDK: the largest buffer FSCTL_GET_REPARSE_POINT will fill.
+	maximumReparseDataBufferSize = 16 * 1024
+)
+
+// ErrPrivilegeNotHeld is returned by CreateSymlink when the caller does not
+// hold SeCreateSymbolicLinkPrivilege and the process is not running with
+// Developer Mode's unprivileged symlink creation enabled.
+var ErrPrivilegeNotHeld = errors.New("a required privilege is not held by the client")
+
+// Readlink reads and decodes the reparse point at path, supporting mount
+// points (junctions), NT symlinks, and WSL LX symlinks.
+func Readlink(path string) (*ReparsePoint, error) {
+	h, err := openReparsePoint(path, windows.GENERIC_READ)
+	if err != nil {
+		return nil, &os.PathError{Op: "readlink", Path: path, Err: err}
+	}
+	defer windows.CloseHandle(h) //nolint:errcheck
+
+	b := make([]byte, maximumReparseDataBufferSize)
+	var bytesReturned uint32
+	err = windows.DeviceIoControl(h, fsctlGetReparsePoint, nil, 0, &b[0], uint32(len(b)), &bytesReturned, nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "readlink", Path: path, Err: err}
+	}
+	return DecodeReparsePoint(b[:bytesReturned])
+}
+
+// CreateSymlink creates an NT symlink at path pointing at target. flags
+// selects whether target is relative to path and whether the symlink refers
+// to a directory or a file. If the calling process lacks
+// SeCreateSymbolicLinkPrivilege and is not running with Developer Mode's
+// unprivileged symlink creation enabled, it returns ErrPrivilegeNotHeld.
+func CreateSymlink(path, target string, flags SymlinkFlags) error {
+	if err := createReparsePlaceholder(path, flags&SymlinkFlagDirectory != 0); err != nil {
+		return err
+	}
+	rp := &ReparsePoint{Target: target, Relative: flags&SymlinkFlagRelative != 0}
+	if err := setReparsePoint(path, EncodeReparsePoint(rp)); err != nil {
+		os.Remove(path) //nolint:errcheck
+		if errors.Is(err, windows.ERROR_PRIVILEGE_NOT_HELD) {
+			return ErrPrivilegeNotHeld
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateJunction creates a mount point (junction) at path pointing at target.
+// target must be an absolute path; junctions always refer to directories.
+func CreateJunction(path, target string) error {
+	if err := createReparsePlaceholder(path, true); err != nil {
+		return err
+	}
+	rp := &ReparsePoint{Target: target, IsMountPoint: true}
+	if err := setReparsePoint(path, EncodeReparsePoint(rp)); err != nil {
+		os.Remove(path) //nolint:errcheck
+		return err
+	}
+	return nil
+}
+
+// CreateLxSymlink creates a WSL/MSYS2-style LX symlink at path pointing at
+// target, as used by Linux filesystems mounted under WSL.
+func CreateLxSymlink(path, target string) error {
+	if err := createReparsePlaceholder(path, false); err != nil {
+		return err
+	}
+	rp := &ReparsePoint{Target: target, IsLxSymlink: true}
+	if err := setReparsePoint(path, EncodeReparsePoint(rp)); err != nil {
+		os.Remove(path) //nolint:errcheck
+		return err
+	}
+	return nil
+}
+
+// createReparsePlaceholder creates the empty file or directory that a
+// reparse point's metadata is attached to.
+func createReparsePlaceholder(path string, dir bool) error {
+	if dir {
+		return os.Mkdir(path, 0)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func setReparsePoint(path string, data []byte) error {
+	h, err := openReparsePoint(path, windows.GENERIC_WRITE)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(h) //nolint:errcheck
+
+	var bytesReturned uint32
+	return windows.DeviceIoControl(h, fsctlSetReparsePoint, &data[0], uint32(len(data)), nil, 0, &bytesReturned, nil)
+}
+
+func openReparsePoint(path string, access uint32) (windows.Handle, error) {
+	path16, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	return windows.CreateFile(
+		path16,
+		access,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_OPEN_REPARSE_POINT|windows.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package winio
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateJunctionAndReadlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := createReparsePlaceholder(target, true); err != nil {
+		t.Fatalf("creating target dir: %v", err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := CreateJunction(link, target); err != nil {
+		t.Fatalf("CreateJunction: %v", err)
+	}
+
+	rp, err := Readlink(link)
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if !rp.IsMountPoint {
+		t.Errorf("expected IsMountPoint, got %+v", rp)
+	}
+}
+
+func TestCreateLxSymlinkAndReadlink(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "lxlink")
+	const target = "/usr/bin/bash"
+	if err := CreateLxSymlink(link, target); err != nil {
+		t.Fatalf("CreateLxSymlink: %v", err)
+	}
+
+	rp, err := Readlink(link)
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if !rp.IsLxSymlink || rp.Target != target {
+		t.Errorf("got %+v, want LX symlink to %q", rp, target)
+	}
+}
+
+func TestCreateSymlinkRelative(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "rellink")
+	const target = `..\sibling\target`
+
+	err := CreateSymlink(link, target, SymlinkFlagRelative)
+	if errors.Is(err, ErrPrivilegeNotHeld) {
+		t.Skip("SeCreateSymbolicLinkPrivilege not held")
+	}
+	if err != nil {
+		t.Fatalf("CreateSymlink: %v", err)
+	}
+
+	rp, err := Readlink(link)
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if rp.Target != target {
+		t.Errorf("Target: got %q, want %q", rp.Target, target)
+	}
+}
+
+func TestCreateSymlinkPrivilegeNotHeld(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "symlink")
+	err := CreateSymlink(link, filepath.Join(dir, "target"), 0)
+	if err == nil {
+		// The test machine has SeCreateSymbolicLinkPrivilege or Developer
+		// Mode enabled; nothing more to verify here.
+		return
+	}
+	if !errors.Is(err, ErrPrivilegeNotHeld) {
+		t.Fatalf("got %v, want ErrPrivilegeNotHeld", err)
+	}
+}
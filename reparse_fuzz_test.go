@@ -0,0 +1,21 @@
+//go:build windows
+
+package winio
+
+import "testing"
+
+func FuzzDecodeReparsePoint(f *testing.F) {
+	f.Add(EncodeReparsePoint(&ReparsePoint{Target: `C:\mnt\target`, IsMountPoint: true}))
+	f.Add(EncodeReparsePoint(&ReparsePoint{Target: `C:\Windows\System32`}))
+	f.Add(EncodeReparsePoint(&ReparsePoint{Target: `..\relative\target`}))
+	f.Add(EncodeReparsePoint(&ReparsePoint{Target: "/usr/bin/bash", IsLxSymlink: true}))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		// DecodeReparsePoint must never panic on arbitrary input; any
+		// malformed buffer should surface as an error.
+		rp, err := DecodeReparsePoint(b)
+		if err != nil && rp != nil {
+			t.Fatalf("got both a ReparsePoint and an error: %+v, %v", rp, err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+//go:build windows
+
+package winio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeReparsePointDataUnknownTagIsGeneric(t *testing.T) {
+	const tag = 0x9000001A // unrecognized, not a name surrogate
+	payload := []byte{1, 2, 3, 4}
+
+	rp, err := DecodeReparsePointData(tag, payload)
+	if err != nil {
+		t.Fatalf("DecodeReparsePointData: %v", err)
+	}
+	if rp.Generic == nil {
+		t.Fatalf("expected a Generic reparse point, got %+v", rp)
+	}
+	if rp.Generic.ReparseTag() != tag {
+		t.Errorf("ReparseTag: got %x, want %x", rp.Generic.ReparseTag(), tag)
+	}
+	if !bytes.Equal(rp.Generic.Data, payload) {
+		t.Errorf("Data: got %v, want %v", rp.Generic.Data, payload)
+	}
+}
+
+func TestRegisterReparseDecoder(t *testing.T) {
+	const tag = 0x9000001B
+	want := &ReparsePoint{Target: "decoded-by-plugin"}
+
+	RegisterReparseDecoder(tag, func(b []byte) (*ReparsePoint, error) {
+		return want, nil
+	})
+
+	rp, err := DecodeReparsePointData(tag, []byte{0xAA})
+	if err != nil {
+		t.Fatalf("DecodeReparsePointData: %v", err)
+	}
+	if rp != want {
+		t.Errorf("got %+v, want the registered decoder's result", rp)
+	}
+}
+
+func TestIsNameSurrogate(t *testing.T) {
+	cases := []struct {
+		name string
+		tag  uint32
+		want bool
+	}{
+		{"mount point", reparseTagMountPoint, true},
+		{"nt symlink", reparseTagSymlink, true},
+		{"lx symlink", reparseTagLxSymlink, true},
+		{"wci link", 0xA0000027, true},
+		{"appexeclink", 0x8000001B, false},
+	}
+	for _, c := range cases {
+		if got := IsNameSurrogate(c.tag); got != c.want {
+			t.Errorf("%s: IsNameSurrogate(%x) = %v, want %v", c.name, c.tag, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package winio
+
+import "testing"
+
+func TestNormalizeNTPathDriveLetter(t *testing.T) {
+	got, err := NormalizeNTPath(`\??\C:\Users\foo`)
+	if err != nil {
+		t.Fatalf("NormalizeNTPath: %v", err)
+	}
+	if want := `C:\Users\foo`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeNTPathUNC(t *testing.T) {
+	got, err := NormalizeNTPath(`\??\UNC\server\share\dir`)
+	if err != nil {
+		t.Fatalf("NormalizeNTPath: %v", err)
+	}
+	if want := `\\server\share\dir`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeNTPathNonNT(t *testing.T) {
+	got, err := NormalizeNTPath(`C:\already\dos`)
+	if err != nil {
+		t.Fatalf("NormalizeNTPath: %v", err)
+	}
+	if want := `C:\already\dos`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDecodeReparsePointWithOptionsVolumeGUIDRoundTrip(t *testing.T) {
+	// A Volume GUID substitute name, as a junction pointing at a volume
+	// (e.g. a VSS shadow copy) would be decoded.
+	target := `\??\Volume{3a2b1c4d-0000-0000-0000-0000deadbeef}\Users\foo`
+	encoded := EncodeReparsePoint(&ReparsePoint{Target: target, IsMountPoint: true})
+
+	decoded, err := DecodeReparsePoint(encoded)
+	if err != nil {
+		t.Fatalf("DecodeReparsePoint: %v", err)
+	}
+	if decoded.Target != target {
+		t.Errorf("Target: got %q, want %q", decoded.Target, target)
+	}
+
+	// With ResolveVolumeGUID the caller opts in to resolving the volume; since
+	// this GUID doesn't correspond to a real volume on the test machine, we
+	// expect the lookup to fail rather than be silently ignored.
+	if _, err := DecodeReparsePointWithOptions(encoded, DecodeReparsePointOptions{ResolveVolumeGUID: true}); err == nil {
+		t.Fatalf("expected an error resolving a nonexistent volume, got nil")
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package winio
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// symlinkFlagsOffset is where the Flags field lands in a symlink
+// reparse buffer: right after the 16-byte reparseDataBuffer header.
+const symlinkFlagsOffset = 16
+
+func TestEncodeWindowsReparsePointRelativeFlag(t *testing.T) {
+	rp := &ReparsePoint{Target: `sibling\target`, Relative: true}
+	encoded := EncodeReparsePoint(rp)
+
+	flags := binary.LittleEndian.Uint32(encoded[symlinkFlagsOffset : symlinkFlagsOffset+4])
+	if flags&1 == 0 {
+		t.Errorf("expected the relative bit set in Flags, got %#x", flags)
+	}
+
+	decoded, err := DecodeReparsePoint(encoded)
+	if err != nil {
+		t.Fatalf("DecodeReparsePoint: %v", err)
+	}
+	if decoded.Target != rp.Target {
+		t.Errorf("Target: got %q, want %q", decoded.Target, rp.Target)
+	}
+}
+
+func TestEncodeWindowsReparsePointAbsoluteHeuristic(t *testing.T) {
+	rp := &ReparsePoint{Target: `C:\abs\target`}
+	encoded := EncodeReparsePoint(rp)
+
+	flags := binary.LittleEndian.Uint32(encoded[symlinkFlagsOffset : symlinkFlagsOffset+4])
+	if flags&1 != 0 {
+		t.Errorf("expected the relative bit clear for an absolute target, got %#x", flags)
+	}
+}